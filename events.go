@@ -0,0 +1,274 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// DecodeEventLog decodes the non-indexed data and indexed topics of a log entry against the
+// event named eventName in the contract's ABI, returning the arguments keyed by name.
+func (gc *GenericContract) DecodeEventLog(eventName string, logEntry types.Log) (map[string]interface{}, error) {
+	eventDef, ok := gc.parsedABI.Events[eventName]
+	if !ok {
+		return nil, fmt.Errorf("event %q not found in ABI", eventName)
+	}
+
+	result := make(map[string]interface{})
+	if err := gc.parsedABI.UnpackIntoMap(result, eventName, logEntry.Data); err != nil {
+		return nil, fmt.Errorf("failed to unpack data for event %q: %v", eventName, err)
+	}
+
+	var indexed abi.Arguments
+	for _, arg := range eventDef.Inputs {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		}
+	}
+	if len(indexed) > 0 {
+		// Anonymous events omit the signature hash from topic0, so their indexed args start
+		// right at Topics[0] instead of Topics[1:].
+		topics := logEntry.Topics
+		if !eventDef.Anonymous {
+			if len(topics) < 1 {
+				return nil, fmt.Errorf("log for event %q has no topics", eventName)
+			}
+			topics = topics[1:]
+		}
+		if err := abi.ParseTopicsIntoMap(result, indexed, topics); err != nil {
+			return nil, fmt.Errorf("failed to parse indexed topics for event %q: %v", eventName, err)
+		}
+	}
+
+	return result, nil
+}
+
+// matchEventByTopic finds the event in parsedABI whose signature hash matches logEntry's topic0,
+// for contexts (like a transaction receipt) where the event name being decoded isn't already
+// known. Anonymous events have no signature topic to match against and are never returned.
+func matchEventByTopic(parsedABI abi.ABI, logEntry types.Log) (string, bool) {
+	if len(logEntry.Topics) == 0 {
+		return "", false
+	}
+	for name, eventDef := range parsedABI.Events {
+		if !eventDef.Anonymous && eventDef.ID == logEntry.Topics[0] {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// SubscribeEvents replays logs for eventName between fromBlock and toBlock, then, if watch is
+// set, keeps streaming newly mined matching logs until ctx is cancelled (even when toBlock is a
+// fixed block rather than "latest"). query filters the event's indexed arguments by position,
+// following the semantics of bind.BoundContract.FilterLogs.
+//
+// The historical replay is done with a direct, bounded eth_getLogs call rather than
+// bind.BoundContract.FilterLogs: that method's returned channel is fed from a subscription that
+// is never closed once the buffered logs are drained, so ranging over it blocks forever instead
+// of completing.
+func (gc *GenericContract) SubscribeEvents(ctx context.Context, eventName string, fromBlock, toBlock *big.Int, watch bool, query ...[]interface{}) (<-chan types.Log, error) {
+	eventDef, ok := gc.parsedABI.Events[eventName]
+	if !ok {
+		return nil, fmt.Errorf("event %q not found in ABI", eventName)
+	}
+
+	topics, err := abi.MakeTopics(query...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build topic filter for event %q: %v", eventName, err)
+	}
+	// Anonymous events have no signature hash in topic0, so the indexed-argument filters occupy
+	// topics[0:] instead of being shifted right to make room for the event ID.
+	filterTopics := topics
+	if !eventDef.Anonymous {
+		filterTopics = append([][]common.Hash{{eventDef.ID}}, topics...)
+	}
+
+	historical, err := gc.backend.FilterLogs(ctx, ethereum.FilterQuery{
+		Addresses: []common.Address{gc.address},
+		Topics:    filterTopics,
+		FromBlock: fromBlock,
+		ToBlock:   toBlock,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to filter logs for event %q: %v", eventName, err)
+	}
+
+	logs := make(chan types.Log)
+
+	go func() {
+		defer close(logs)
+
+		for _, logEntry := range historical {
+			select {
+			case logs <- logEntry:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		if !watch {
+			// The caller only asked for the historical range; there is nothing further to stream.
+			return
+		}
+
+		live, liveSub, err := gc.contract.WatchLogs(&bind.WatchOpts{Context: ctx}, eventName, query...)
+		if err != nil {
+			log.Printf("Failed to subscribe to live logs for event %q: %v", eventName, err)
+			return
+		}
+		defer liveSub.Unsubscribe()
+
+		for {
+			select {
+			case logEntry, ok := <-live:
+				if !ok {
+					return
+				}
+				select {
+				case logs <- logEntry:
+				case <-ctx.Done():
+					return
+				}
+			case err := <-liveSub.Err():
+				if err != nil {
+					log.Printf("Event subscription for %q ended: %v", eventName, err)
+				}
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return logs, nil
+}
+
+// convertTopicValue converts a raw -topics string into the Go type abi.MakeTopics expects for
+// arg's type, so indexed addresses/integers/hashes are matched by value instead of being
+// keccak-hashed as arbitrary strings.
+func convertTopicValue(arg abi.Argument, raw string) (interface{}, error) {
+	switch arg.Type.T {
+	case abi.AddressTy:
+		if !common.IsHexAddress(raw) {
+			return nil, fmt.Errorf("expected an address, got %q", raw)
+		}
+		return common.HexToAddress(raw), nil
+	case abi.UintTy, abi.IntTy:
+		n, ok := new(big.Int).SetString(raw, 0)
+		if !ok {
+			return nil, fmt.Errorf("expected an integer, got %q", raw)
+		}
+		return n, nil
+	case abi.BoolTy:
+		return raw == "true", nil
+	case abi.FixedBytesTy, abi.HashTy:
+		return common.HexToHash(raw), nil
+	default:
+		return raw, nil
+	}
+}
+
+// runEventMode drives the -event CLI mode: it parses the block-range and topic flags, replays
+// matching historical logs, optionally keeps watching for new ones, and prints each decoded log
+// using the same rendering style as CallViewFunction results.
+func runEventMode(contract *GenericContract, client *ethclient.Client, eventName, fromBlockFlag, toBlockFlag, topicsFlag string, watch bool) {
+	var fromBlock, toBlock *big.Int
+
+	if fromBlockFlag != "" {
+		n, success := new(big.Int).SetString(fromBlockFlag, 10)
+		if !success {
+			log.Fatalf("Failed to parse -from-block value: %s", fromBlockFlag)
+		}
+		fromBlock = n
+	}
+
+	if toBlockFlag != "" {
+		n, success := new(big.Int).SetString(toBlockFlag, 10)
+		if !success {
+			log.Fatalf("Failed to parse -to-block value: %s", toBlockFlag)
+		}
+		toBlock = n
+	} else if !watch {
+		head, err := client.BlockNumber(context.Background())
+		if err != nil {
+			log.Fatalf("Failed to fetch latest block number: %v", err)
+		}
+		toBlock = new(big.Int).SetUint64(head)
+	}
+
+	var query [][]interface{}
+	if topicsFlag != "" {
+		eventDef, ok := contract.parsedABI.Events[eventName]
+		if !ok {
+			log.Fatalf("Event %q not found in ABI", eventName)
+		}
+
+		var firstIndexed *abi.Argument
+		for _, arg := range eventDef.Inputs {
+			if arg.Indexed {
+				firstIndexed = &arg
+				break
+			}
+		}
+		if firstIndexed == nil {
+			log.Fatalf("Event %q has no indexed arguments to filter with -topics", eventName)
+		}
+
+		var values []interface{}
+		for _, v := range strings.Split(topicsFlag, ",") {
+			converted, err := convertTopicValue(*firstIndexed, strings.TrimSpace(v))
+			if err != nil {
+				log.Fatalf("Failed to parse -topics value %q for argument %q: %v", v, firstIndexed.Name, err)
+			}
+			values = append(values, converted)
+		}
+		query = append(query, values)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if watch {
+		// Allow Ctrl+C to stop a long-running watch cleanly.
+		sigCh := make(chan os.Signal, 1)
+		signal.Notify(sigCh, os.Interrupt)
+		go func() {
+			<-sigCh
+			cancel()
+		}()
+	}
+
+	logs, err := contract.SubscribeEvents(ctx, eventName, fromBlock, toBlock, watch, query...)
+	if err != nil {
+		log.Fatalf("Failed to subscribe to event %q: %v", eventName, err)
+	}
+
+	fmt.Printf("Decoding event '%s'\n", eventName)
+
+	for logEntry := range logs {
+		decoded, err := contract.DecodeEventLog(eventName, logEntry)
+		if err != nil {
+			fmt.Printf("Failed to decode log at block %d, tx %s: %v\n", logEntry.BlockNumber, logEntry.TxHash.Hex(), err)
+			continue
+		}
+
+		fmt.Printf("Block %d | Tx %s | LogIndex %d\n", logEntry.BlockNumber, logEntry.TxHash.Hex(), logEntry.Index)
+		for name, value := range decoded {
+			var b strings.Builder
+			renderTextValue(&b, value, RenderOptions{}, 1)
+			fmt.Printf("  %s: %s\n", name, b.String())
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// parseBlockSpec parses the -block flag: "", "latest" (nil, meaning latest), "earliest", a
+// decimal block number, or "hash:0x..." resolved against client.
+func parseBlockSpec(ctx context.Context, client *ethclient.Client, spec string) (*big.Int, error) {
+	switch {
+	case spec == "" || spec == "latest":
+		return nil, nil
+	case spec == "earliest":
+		return big.NewInt(0), nil
+	case strings.HasPrefix(spec, "hash:"):
+		hash := common.HexToHash(strings.TrimPrefix(spec, "hash:"))
+		header, err := client.HeaderByHash(ctx, hash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve block hash %s: %v", hash.Hex(), err)
+		}
+		return header.Number, nil
+	default:
+		n, success := new(big.Int).SetString(spec, 10)
+		if !success {
+			return nil, fmt.Errorf("invalid block %q: expected a number, latest, earliest, or hash:0x...", spec)
+		}
+		return n, nil
+	}
+}
+
+// blockRange is a "start:end:step" sweep of block numbers to sample.
+type blockRange struct {
+	start *big.Int
+	end   *big.Int
+	step  *big.Int
+}
+
+// parseBlockRange parses the -block-range flag's "start:end:step" syntax.
+func parseBlockRange(spec string) (*blockRange, error) {
+	parts := strings.Split(spec, ":")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("expected \"start:end:step\", got %q", spec)
+	}
+
+	start, success := new(big.Int).SetString(strings.TrimSpace(parts[0]), 10)
+	if !success {
+		return nil, fmt.Errorf("invalid start block %q", parts[0])
+	}
+	end, success := new(big.Int).SetString(strings.TrimSpace(parts[1]), 10)
+	if !success {
+		return nil, fmt.Errorf("invalid end block %q", parts[1])
+	}
+	step, success := new(big.Int).SetString(strings.TrimSpace(parts[2]), 10)
+	if !success || step.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid step %q: must be a positive integer", parts[2])
+	}
+
+	return &blockRange{start: start, end: end, step: step}, nil
+}
+
+// resultsEqual reports whether two CallViewFunction results have the same string representation.
+func resultsEqual(a, b []interface{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if fmt.Sprintf("%v", a[i]) != fmt.Sprintf("%v", b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// findChangeBlock binary-searches (low, high] for the first block whose result no longer
+// matches lowResult (the result already known at low), assuming the value changes at most once
+// in the range.
+func findChangeBlock(callAt func(*big.Int) ([]interface{}, error), low, high *big.Int, lowResult []interface{}) (*big.Int, []interface{}, error) {
+	highResult, err := callAt(high)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for new(big.Int).Sub(high, low).Cmp(big.NewInt(1)) > 0 {
+		mid := new(big.Int).Add(low, high)
+		mid.Div(mid, big.NewInt(2))
+
+		midResult, err := callAt(mid)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if resultsEqual(lowResult, midResult) {
+			low = mid
+		} else {
+			high = mid
+			highResult = midResult
+		}
+	}
+
+	return high, highResult, nil
+}
+
+// runHistoryMode drives the -block-range CLI mode: it samples functionName at every step-th
+// block between start and end (inclusive), printing one row per sampled block as CSV or, when
+// format is "json", as a JSON object per line. With diff set, the first sampled block is printed
+// and after it only blocks where the result changed, binary-searching for the exact transition
+// block when step skips over more than one block at a time.
+func runHistoryMode(contract *GenericContract, client *ethclient.Client, functionName string, args []interface{}, rangeSpec, format string, diff bool) {
+	br, err := parseBlockRange(rangeSpec)
+	if err != nil {
+		log.Fatalf("Failed to parse -block-range: %v", err)
+	}
+
+	outputs := contract.parsedABI.Methods[functionName].Outputs
+	asJSON := format == "json"
+
+	ctx := context.Background()
+
+	callAt := func(block *big.Int) ([]interface{}, error) {
+		return contract.CallViewFunction(functionName, block, args...)
+	}
+
+	csvWriter := csv.NewWriter(os.Stdout)
+
+	printRow := func(block *big.Int, result []interface{}) {
+		timestamp := uint64(0)
+		if header, err := client.HeaderByNumber(ctx, block); err != nil {
+			log.Printf("Warning: failed to fetch timestamp for block %s: %v", block, err)
+		} else {
+			timestamp = header.Time
+		}
+
+		if asJSON {
+			row := map[string]interface{}{"block": block.String(), "timestamp": timestamp}
+			for i, v := range result {
+				row[outputName(outputs, i)] = normalizeValue(v, RenderOptions{})
+			}
+			data, err := json.Marshal(row)
+			if err != nil {
+				log.Printf("Warning: failed to marshal JSON row for block %s: %v", block, err)
+				return
+			}
+			fmt.Println(string(data))
+			return
+		}
+
+		fields := make([]string, 0, len(result)+2)
+		fields = append(fields, block.String(), fmt.Sprintf("%d", timestamp))
+		for _, v := range result {
+			fields = append(fields, csvField(normalizeValue(v, RenderOptions{})))
+		}
+		if err := csvWriter.Write(fields); err != nil {
+			log.Printf("Warning: failed to write CSV row for block %s: %v", block, err)
+			return
+		}
+		csvWriter.Flush()
+	}
+
+	if !asJSON {
+		header := make([]string, 0, len(outputs)+2)
+		header = append(header, "block", "timestamp")
+		for i := range outputs {
+			header = append(header, outputName(outputs, i))
+		}
+		if err := csvWriter.Write(header); err != nil {
+			log.Fatalf("Failed to write CSV header: %v", err)
+		}
+		csvWriter.Flush()
+	}
+
+	var prevBlock *big.Int
+	var prevResult []interface{}
+
+	for block := new(big.Int).Set(br.start); block.Cmp(br.end) <= 0; block.Add(block, br.step) {
+		result, err := callAt(block)
+		if err != nil {
+			log.Printf("Warning: call failed at block %s: %v", block, err)
+			continue
+		}
+
+		switch {
+		case !diff:
+			printRow(block, result)
+		case prevResult == nil:
+			printRow(block, result)
+		case !resultsEqual(prevResult, result):
+			changeBlock, changeResult := block, result
+			if br.step.Cmp(big.NewInt(1)) > 0 {
+				if b, r, err := findChangeBlock(callAt, prevBlock, block, prevResult); err != nil {
+					log.Printf("Warning: binary search for change point failed: %v", err)
+				} else {
+					changeBlock, changeResult = b, r
+				}
+			}
+			printRow(changeBlock, changeResult)
+		}
+
+		prevBlock = new(big.Int).Set(block)
+		prevResult = result
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
@@ -17,8 +18,11 @@ import (
 
 // GenericContract is a generic binding to an Ethereum contract
 type GenericContract struct {
-	contract *bind.BoundContract
-	abi      string
+	contract  *bind.BoundContract
+	abi       string
+	parsedABI abi.ABI
+	address   common.Address
+	backend   bind.ContractBackend
 }
 
 // NewGenericContract creates a new instance of a generic contract binding
@@ -27,30 +31,51 @@ func NewGenericContract(address common.Address, abiString string, backend bind.C
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse ABI: %v", err)
 	}
-	
+
 	contract := bind.NewBoundContract(address, parsed, backend, backend, nil)
 	return &GenericContract{
-		contract: contract,
-		abi:      abiString,
+		contract:  contract,
+		abi:       abiString,
+		parsedABI: parsed,
+		address:   address,
+		backend:   backend,
 	}, nil
 }
 
-// CallViewFunction calls a view function on the contract and returns the result
-func (gc *GenericContract) CallViewFunction(functionName string, args ...interface{}) ([]interface{}, error) {
+// CallViewFunction calls a view function on the contract at blockNumber (nil for latest) and
+// returns the result.
+func (gc *GenericContract) CallViewFunction(functionName string, blockNumber *big.Int, args ...interface{}) ([]interface{}, error) {
 	var out []interface{}
-	err := gc.contract.Call(&bind.CallOpts{Context: context.Background()}, &out, functionName, args...)
+	err := gc.contract.Call(&bind.CallOpts{Context: context.Background(), BlockNumber: blockNumber}, &out, functionName, args...)
 	return out, err
 }
 
+// CallRaw calls functionName at blockNumber and returns the still-ABI-encoded return data
+// without unpacking it, for -format=raw-hex and debugging decode mismatches.
+func (gc *GenericContract) CallRaw(ctx context.Context, blockNumber *big.Int, functionName string, args ...interface{}) ([]byte, error) {
+	input, err := gc.parsedABI.Pack(functionName, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode call to %q: %v", functionName, err)
+	}
+
+	output, err := gc.backend.CallContract(ctx, ethereum.CallMsg{To: &gc.address, Data: input}, blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("call to %q failed: %v", functionName, err)
+	}
+	return output, nil
+}
+
 // Example ABIs for common contract types
 var (
-	// Simple ERC20 token ABI with basic view functions
+	// Simple ERC20 token ABI with basic view functions and the standard Transfer/Approval events
 	ERC20ABI = `[
 		{"constant":true,"inputs":[],"name":"name","outputs":[{"name":"","type":"string"}],"type":"function"},
 		{"constant":true,"inputs":[],"name":"symbol","outputs":[{"name":"","type":"string"}],"type":"function"},
 		{"constant":true,"inputs":[],"name":"decimals","outputs":[{"name":"","type":"uint8"}],"type":"function"},
 		{"constant":true,"inputs":[],"name":"totalSupply","outputs":[{"name":"","type":"uint256"}],"type":"function"},
-		{"constant":true,"inputs":[{"name":"_owner","type":"address"}],"name":"balanceOf","outputs":[{"name":"balance","type":"uint256"}],"type":"function"}
+		{"constant":true,"inputs":[{"name":"_owner","type":"address"}],"name":"balanceOf","outputs":[{"name":"balance","type":"uint256"}],"type":"function"},
+		{"anonymous":false,"inputs":[{"indexed":true,"name":"from","type":"address"},{"indexed":true,"name":"to","type":"address"},{"indexed":false,"name":"value","type":"uint256"}],"name":"Transfer","type":"event"},
+		{"anonymous":false,"inputs":[{"indexed":true,"name":"owner","type":"address"},{"indexed":true,"name":"spender","type":"address"},{"indexed":false,"name":"value","type":"uint256"}],"name":"Approval","type":"event"}
 	]`
 	
 	// Simple storage contract ABI
@@ -113,9 +138,36 @@ func main() {
 	contractTypePtr := flag.String("type", "generic", "Contract type (erc20, storage, generic)")
 	abiFilePtr := flag.String("abi", "", "Path to ABI JSON file (optional)")
 	argsPtr := flag.String("args", "", "Function arguments (comma separated)")
-	convertPtr := flag.Bool("convert", false, "Convert big.Int results to decimal using 10^18 denominator (for tokens)")
+	convertPtr := flag.Bool("convert", false, "Convert big.Int results to decimal using -decimals/-unit as the denominator")
+	formatPtr := flag.String("format", "text", "Output format: text, json, csv, or raw-hex")
+	decimalsPtr := flag.String("decimals", "18", "Decimal exponent for -convert, or \"auto\" to fetch decimals() (requires -type=erc20)")
+	unitPtr := flag.String("unit", "", "Preset -convert denominator: wei, gwei, ether, or token (uses -decimals)")
 	helpPtr := flag.Bool("help", false, "Display help information")
-	
+	eventPtr := flag.String("event", "", "Event name to decode/subscribe to against the loaded ABI (switches to event mode)")
+	fromBlockPtr := flag.String("from-block", "", "Start block for event queries (decimal block number, default: 0)")
+	toBlockPtr := flag.String("to-block", "", "End block for event queries (decimal block number, default: latest)")
+	watchPtr := flag.Bool("watch", false, "After replaying historical events, keep streaming new matching events")
+	topicsPtr := flag.String("topics", "", "Comma separated values to filter the event's first indexed argument")
+	sendPtr := flag.Bool("send", false, "Send a transaction instead of making a read-only call")
+	keystorePtr := flag.String("keystore", "", "Path to an encrypted JSON keystore file (required with -send)")
+	passphrasePtr := flag.String("passphrase", "", "Passphrase for the keystore file")
+	passphraseFilePtr := flag.String("passphrase-file", "", "Path to a file containing the keystore passphrase")
+	gasLimitPtr := flag.Uint64("gas-limit", 0, "Gas limit for the transaction (0 = estimate)")
+	gasPricePtr := flag.String("gas-price", "", "Legacy gas price in wei")
+	maxFeePtr := flag.String("max-fee", "", "EIP-1559 max fee per gas in wei")
+	maxPriorityFeePtr := flag.String("max-priority-fee", "", "EIP-1559 max priority fee per gas in wei")
+	valuePtr := flag.String("value", "", "Amount of wei to send with the transaction")
+	noncePtr := flag.Int64("nonce", -1, "Transaction nonce (-1 = fetch pending nonce automatically)")
+	chainIDPtr := flag.String("chain-id", "", "Chain ID to sign the transaction for (required with -send)")
+	chainPtr := flag.String("chain", "", "Chain to resolve the ABI against when auto-fetching (mainnet, sepolia, polygon, arbitrum, story); default: auto-detect from -rpc's chain ID")
+	etherscanKeyPtr := flag.String("etherscan-key", "", "Etherscan-compatible API key used when auto-fetching an ABI")
+	batchPtr := flag.String("batch", "", "Path to a batch file of contract,abi,function,args tuples (CSV or JSON) to execute via Multicall3")
+	multicallAddrPtr := flag.String("multicall-address", DefaultMulticall3Address, "Multicall3 contract address to batch calls through")
+	blockPtr := flag.String("block", "", "Block to call/batch against: a number, latest, earliest, or hash:0x... (default: latest)")
+	allowFailurePtr := flag.Bool("allow-failure", false, "In -batch mode, continue past individual call reverts instead of aborting")
+	blockRangePtr := flag.String("block-range", "", "Call the function across a block range \"start:end:step\" and print one row per sampled block")
+	diffPtr := flag.Bool("diff", false, "With -block-range, print the first sampled block and then only blocks where the result changed (binary-searches the exact transition block)")
+
 	// Custom usage message
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Usage: %s -contract=0xContractAddress -function=functionName [-rpc=https://your-ethereum-node] [-type=erc20|storage|generic] [-abi=path/to/abi.json] [-args=arg1,arg2,...] [-convert]\n\n", os.Args[0])
@@ -128,6 +180,18 @@ func main() {
 		fmt.Fprintf(os.Stderr, "    %s -contract=0x123... -function=balanceOf -type=erc20 -args=0x456...\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  Call a function and convert result to decimal:\n")
 		fmt.Fprintf(os.Stderr, "    %s -contract=0x123... -function=balanceOf -type=erc20 -args=0x456... -convert\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  Decode historical Transfer events and keep watching for new ones:\n")
+		fmt.Fprintf(os.Stderr, "    %s -contract=0x123... -type=erc20 -event=Transfer -from-block=18000000 -watch\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  Send a transaction signed by a keystore file:\n")
+		fmt.Fprintf(os.Stderr, "    %s -contract=0x123... -function=transfer -abi=./erc20.json -args=0x456...,1000 -send -keystore=./key.json -passphrase-file=./pass.txt -chain-id=1\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  Call a function on a contract with no local ABI, auto-fetching it (chain auto-detected from -rpc):\n")
+		fmt.Fprintf(os.Stderr, "    %s -contract=0x123... -function=totalSupply -etherscan-key=YOUR_KEY\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  Batch many calls across contracts in one round-trip:\n")
+		fmt.Fprintf(os.Stderr, "    %s -batch=./calls.csv -rpc=https://your-ethereum-node -allow-failure\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  Track how a value changed over a block range:\n")
+		fmt.Fprintf(os.Stderr, "    %s -contract=0x123... -function=totalSupply -type=erc20 -block-range=18000000:19000000:10000 -diff\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  Render a balance as JSON using the token's own decimals:\n")
+		fmt.Fprintf(os.Stderr, "    %s -contract=0x123... -function=balanceOf -type=erc20 -args=0x456... -format=json -convert -decimals=auto\n", os.Args[0])
 	}
 	
 	// Parse command line arguments
@@ -139,6 +203,18 @@ func main() {
 		os.Exit(0)
 	}
 	
+	// Batch mode: execute many calls across contracts via Multicall3 in one round-trip
+	if *batchPtr != "" {
+		client, err := ethclient.Dial(*rpcURLPtr)
+		if err != nil {
+			log.Fatalf("Failed to connect to the Ethereum client: %v", err)
+		}
+		fmt.Printf("Connected to Ethereum node at %s\n", *rpcURLPtr)
+
+		runBatchMode(client, *batchPtr, *multicallAddrPtr, *blockPtr, *allowFailurePtr)
+		return
+	}
+
 	// Validate required parameters
 	if *contractAddrPtr == "" {
 		fmt.Println("Error: Contract address is required")
@@ -146,7 +222,7 @@ func main() {
 		os.Exit(1)
 	}
 	
-	if *functionNamePtr == "" {
+	if *functionNamePtr == "" && *eventPtr == "" {
 		fmt.Println("Error: Function name is required")
 		flag.Usage()
 		os.Exit(1)
@@ -180,14 +256,21 @@ func main() {
 			log.Fatalf("Failed to read ABI file: %v", err)
 		}
 		abiString = string(abiData)
+	} else if *contractTypePtr == "generic" || *contractTypePtr == "" {
+		// No ABI file and no known type (the default): try to auto-fetch and cache the real
+		// ABI first. This is the common path, so every ordinary call with -type=generic makes
+		// outbound Sourcify/Etherscan requests (a warm bytecode-hash cache hit skips the network
+		// call); pass -abi or a known -type to call without any network lookup.
+		resolved, err := ResolveABI(context.Background(), client, contractAddress, *chainPtr, *etherscanKeyPtr)
+		if err != nil {
+			fmt.Printf("Warning: failed to auto-fetch ABI, falling back to function-name-only ABI: %v\n", err)
+			abiString = strings.Replace(getContractABI(*contractTypePtr), "FUNCTION_PLACEHOLDER", *functionNamePtr, 1)
+		} else {
+			abiString = resolved
+		}
 	} else {
 		// Use predefined ABI based on contract type
 		abiString = getContractABI(*contractTypePtr)
-		
-		// If using generic ABI, replace function placeholder with actual function name
-		if *contractTypePtr == "generic" || *contractTypePtr == "" {
-			abiString = strings.Replace(abiString, "FUNCTION_PLACEHOLDER", *functionNamePtr, 1)
-		}
 	}
 	
 	// Create a new instance of the generic contract bound to the specific deployed contract
@@ -196,6 +279,12 @@ func main() {
 		log.Fatalf("Failed to instantiate the contract: %v", err)
 	}
 	
+	// Event mode: decode historical logs and/or stream new ones instead of calling a view function
+	if *eventPtr != "" {
+		runEventMode(contract, client, *eventPtr, *fromBlockPtr, *toBlockPtr, *topicsPtr, *watchPtr)
+		return
+	}
+
 	// Prepare function arguments
 	var functionArgs []interface{}
 	if *argsPtr != "" {
@@ -227,62 +316,65 @@ func main() {
 		}
 	}
 	
-	// Call the view function
+	// Transaction mode: sign and send a state-changing call instead of reading one
+	if *sendPtr {
+		runSendMode(contract, client, sendOpts{
+			functionName:   *functionNamePtr,
+			keystorePath:   *keystorePtr,
+			passphrase:     *passphrasePtr,
+			passphraseFile: *passphraseFilePtr,
+			gasLimit:       *gasLimitPtr,
+			gasPrice:       *gasPricePtr,
+			maxFee:         *maxFeePtr,
+			maxPriorityFee: *maxPriorityFeePtr,
+			value:          *valuePtr,
+			nonce:          *noncePtr,
+			chainID:        *chainIDPtr,
+		}, functionArgs...)
+		return
+	}
+
+	// Historical/archive mode: call the function across a block range instead of once
+	if *blockRangePtr != "" {
+		runHistoryMode(contract, client, *functionNamePtr, functionArgs, *blockRangePtr, *formatPtr, *diffPtr)
+		return
+	}
+
+	blockNumber, err := parseBlockSpec(context.Background(), client, *blockPtr)
+	if err != nil {
+		log.Fatalf("Failed to parse -block value: %v", err)
+	}
+
+	renderer, err := NewRenderer(*formatPtr)
+	if err != nil {
+		log.Fatalf("Invalid -format: %v", err)
+	}
+
+	renderOpts := RenderOptions{Convert: *convertPtr || *unitPtr != ""}
+	if renderOpts.Convert {
+		renderOpts.Decimals, err = resolveDecimals(contract, *unitPtr, *decimalsPtr, *contractTypePtr)
+		if err != nil {
+			log.Fatalf("Failed to resolve decimals: %v", err)
+		}
+	}
+
 	fmt.Printf("Calling function '%s' with %d arguments\n", *functionNamePtr, len(functionArgs))
-	results, err := contract.CallViewFunction(*functionNamePtr, functionArgs...)
+
+	var results []interface{}
+	if *formatPtr == "raw-hex" {
+		renderOpts.Raw, err = contract.CallRaw(context.Background(), blockNumber, *functionNamePtr, functionArgs...)
+	} else {
+		results, err = contract.CallViewFunction(*functionNamePtr, blockNumber, functionArgs...)
+	}
 	if err != nil {
 		log.Fatalf("Failed to call function '%s': %v", *functionNamePtr, err)
 	}
-	
-	// Display results
+
 	fmt.Println("Function returned successfully!")
-	
-	// Create 10^18 constant for potential conversion
-	exp18 := big.NewInt(10)
-	exp18.Exp(exp18, big.NewInt(18), nil)
-	
-	for i, result := range results {
-		fmt.Printf("Result[%d]: ", i)
-		
-		switch v := result.(type) {
-		case *big.Int:
-			fmt.Printf("%s (big.Int)\n", v.String())
-			
-			// Also display value / 10^18 (common for token amounts)
-			if *convertPtr && v.Cmp(big.NewInt(0)) > 0 {
-				// Calculate integer part (v / 10^18)
-				intPart := new(big.Int).Div(new(big.Int).Set(v), exp18)
-				
-				// Calculate fractional part (v % 10^18)
-				fracPart := new(big.Int).Mod(new(big.Int).Set(v), exp18)
-				
-				// Format the fractional part with leading zeros
-				fracStr := fracPart.String()
-				for len(fracStr) < 18 {
-					fracStr = "0" + fracStr
-				}
-				
-				// Trim trailing zeros
-				for len(fracStr) > 0 && fracStr[len(fracStr)-1] == '0' {
-					fracStr = fracStr[:len(fracStr)-1]
-				}
-				
-				if len(fracStr) > 0 {
-					fmt.Printf("           = %s.%s (decimal)\n", intPart.String(), fracStr)
-				} else {
-					fmt.Printf("           = %s (decimal)\n", intPart.String())
-				}
-			}
-		case string:
-			fmt.Printf("%s (string)\n", v)
-		case []byte:
-			fmt.Printf("0x%x (bytes)\n", v)
-		case common.Address:
-			fmt.Printf("%s (address)\n", v.Hex())
-		case bool:
-			fmt.Printf("%t (bool)\n", v)
-		default:
-			fmt.Printf("%v (type: %T)\n", v, v)
-		}
+
+	output, err := renderer.Render(contract.parsedABI.Methods[*functionNamePtr].Outputs, results, renderOpts)
+	if err != nil {
+		log.Fatalf("Failed to render result: %v", err)
 	}
+	fmt.Print(output)
 }
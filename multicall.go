@@ -0,0 +1,281 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// DefaultMulticall3Address is the canonical Multicall3 deployment address, present at the same
+// address on most EVM chains (including Story) via deterministic CREATE2 deployment.
+const DefaultMulticall3Address = "0xcA11bde05977b3631167028862bE2a173976CA11"
+
+// multicall3ABI is the minimal Multicall3 ABI needed to batch calls through aggregate3.
+const multicall3ABI = `[
+	{
+		"inputs": [{
+			"components": [
+				{"internalType": "address", "name": "target", "type": "address"},
+				{"internalType": "bool", "name": "allowFailure", "type": "bool"},
+				{"internalType": "bytes", "name": "callData", "type": "bytes"}
+			],
+			"internalType": "struct IMulticall3.Call3[]",
+			"name": "calls",
+			"type": "tuple[]"
+		}],
+		"name": "aggregate3",
+		"outputs": [{
+			"components": [
+				{"internalType": "bool", "name": "success", "type": "bool"},
+				{"internalType": "bytes", "name": "returnData", "type": "bytes"}
+			],
+			"internalType": "struct IMulticall3.Result[]",
+			"name": "returnData",
+			"type": "tuple[]"
+		}],
+		"stateMutability": "payable",
+		"type": "function"
+	}
+]`
+
+// multicall3Call mirrors the Solidity IMulticall3.Call3 struct for ABI encoding.
+type multicall3Call struct {
+	Target       common.Address
+	AllowFailure bool
+	CallData     []byte
+}
+
+// Multicall3Result is the decoded outcome of a single batched call.
+type Multicall3Result struct {
+	Success    bool
+	ReturnData []byte
+}
+
+// Multicaller batches calls to many (possibly unrelated) contracts into a single eth_call
+// against a Multicall3 aggregator contract.
+type Multicaller struct {
+	backend bind.ContractBackend
+	address common.Address
+	abi     abi.ABI
+}
+
+// NewMulticaller creates a Multicaller that aggregates calls through the Multicall3 contract
+// deployed at address.
+func NewMulticaller(backend bind.ContractBackend, address common.Address) (*Multicaller, error) {
+	parsed, err := abi.JSON(strings.NewReader(multicall3ABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Multicall3 ABI: %v", err)
+	}
+
+	return &Multicaller{backend: backend, address: address, abi: parsed}, nil
+}
+
+// batchSubcall is one ABI-encodable call destined for a single target contract.
+type batchSubcall struct {
+	target   common.Address
+	function string
+	abi      abi.ABI
+}
+
+// Aggregate ABI-encodes each subcall with its own per-target ABI, executes them all through a
+// single aggregate3 call at blockNumber (nil for latest), and returns one Multicall3Result per
+// subcall in order.
+func (m *Multicaller) Aggregate(ctx context.Context, calls []batchSubcall, args [][]interface{}, blockNumber *big.Int, allowFailure bool) ([]Multicall3Result, error) {
+	if len(calls) != len(args) {
+		return nil, fmt.Errorf("calls and args length mismatch: %d vs %d", len(calls), len(args))
+	}
+
+	call3s := make([]multicall3Call, len(calls))
+	for i, c := range calls {
+		data, err := c.abi.Pack(c.function, args[i]...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode call %d (%s.%s): %v", i, c.target.Hex(), c.function, err)
+		}
+		call3s[i] = multicall3Call{Target: c.target, AllowFailure: allowFailure, CallData: data}
+	}
+
+	input, err := m.abi.Pack("aggregate3", call3s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode aggregate3 call: %v", err)
+	}
+
+	output, err := m.backend.CallContract(ctx, ethereum.CallMsg{To: &m.address, Data: input}, blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("aggregate3 call failed: %v", err)
+	}
+
+	raw, err := m.abi.Unpack("aggregate3", output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode aggregate3 output: %v", err)
+	}
+
+	rv := reflect.ValueOf(raw[0])
+	results := make([]Multicall3Result, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		elem := rv.Index(i)
+		results[i] = Multicall3Result{
+			Success:    elem.FieldByName("Success").Bool(),
+			ReturnData: elem.FieldByName("ReturnData").Bytes(),
+		}
+	}
+
+	return results, nil
+}
+
+// batchRow is one line of a -batch file: a contract/ABI/function/args tuple to execute through
+// the Multicaller.
+type batchRow struct {
+	Contract string   `json:"contract"`
+	ABI      string   `json:"abi"`
+	Function string   `json:"function"`
+	Args     []string `json:"args,omitempty"`
+}
+
+// loadBatchRows reads a -batch file, supporting either a JSON array of batchRow objects or plain
+// CSV lines of the form "contract,abi,function,arg1,arg2,...".
+func loadBatchRows(path string) ([]batchRow, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read batch file: %v", err)
+	}
+
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "[") {
+		var rows []batchRow
+		if err := json.Unmarshal(data, &rows); err != nil {
+			return nil, fmt.Errorf("failed to parse batch file as JSON: %v", err)
+		}
+		return rows, nil
+	}
+
+	var rows []batchRow
+	for i, line := range strings.Split(trimmed, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, ",")
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("batch file line %d: expected at least contract,abi,function", i+1)
+		}
+		rows = append(rows, batchRow{
+			Contract: strings.TrimSpace(fields[0]),
+			ABI:      strings.TrimSpace(fields[1]),
+			Function: strings.TrimSpace(fields[2]),
+			Args:     fields[3:],
+		})
+	}
+	return rows, nil
+}
+
+// runBatchMode drives the -batch CLI mode: it loads the batch file, resolves each row's ABI and
+// arguments, executes them all through a Multicaller, and prints each result using the same
+// type-switch rendering as CallViewFunction.
+func runBatchMode(client *ethclient.Client, batchFile, multicallAddrStr, blockStr string, allowFailure bool) {
+	rows, err := loadBatchRows(batchFile)
+	if err != nil {
+		log.Fatalf("Failed to load batch file: %v", err)
+	}
+	if len(rows) == 0 {
+		log.Fatalf("Batch file %s contained no calls", batchFile)
+	}
+
+	if !common.IsHexAddress(multicallAddrStr) {
+		log.Fatalf("Invalid -multicall-address: %s", multicallAddrStr)
+	}
+	multicaller, err := NewMulticaller(client, common.HexToAddress(multicallAddrStr))
+	if err != nil {
+		log.Fatalf("Failed to create Multicaller: %v", err)
+	}
+
+	blockNumber, err := parseBlockSpec(context.Background(), client, blockStr)
+	if err != nil {
+		log.Fatalf("Failed to parse -block value: %v", err)
+	}
+
+	calls := make([]batchSubcall, len(rows))
+	callArgs := make([][]interface{}, len(rows))
+	for i, row := range rows {
+		if !common.IsHexAddress(row.Contract) {
+			log.Fatalf("Batch row %d: invalid contract address %q", i+1, row.Contract)
+		}
+
+		abiData, err := os.ReadFile(row.ABI)
+		if err != nil {
+			log.Fatalf("Batch row %d: failed to read ABI file %q: %v", i+1, row.ABI, err)
+		}
+		parsedABI, err := abi.JSON(strings.NewReader(string(abiData)))
+		if err != nil {
+			log.Fatalf("Batch row %d: failed to parse ABI file %q: %v", i+1, row.ABI, err)
+		}
+
+		calls[i] = batchSubcall{target: common.HexToAddress(row.Contract), function: row.Function, abi: parsedABI}
+		callArgs[i] = parseArgStrings(row.Args)
+	}
+
+	fmt.Printf("Batching %d calls through Multicall3 at %s\n", len(calls), multicaller.address.Hex())
+
+	results, err := multicaller.Aggregate(context.Background(), calls, callArgs, blockNumber, allowFailure)
+	if err != nil {
+		log.Fatalf("Batch aggregate3 call failed: %v", err)
+	}
+
+	for i, result := range results {
+		fmt.Printf("Call[%d] %s.%s: ", i, rows[i].Contract, rows[i].Function)
+		if !result.Success {
+			fmt.Println("reverted")
+			continue
+		}
+
+		decoded, err := calls[i].abi.Unpack(calls[i].function, result.ReturnData)
+		if err != nil {
+			fmt.Printf("succeeded but failed to decode result: %v\n", err)
+			continue
+		}
+
+		fmt.Println("success")
+		output, err := textRenderer{}.Render(calls[i].abi.Methods[calls[i].function].Outputs, decoded, RenderOptions{})
+		if err != nil {
+			fmt.Printf("failed to render result: %v\n", err)
+			continue
+		}
+		fmt.Print(output)
+	}
+}
+
+// parseArgStrings converts batch-file argument strings into the same Go types that main's
+// -args parsing produces (addresses, big.Ints, or raw strings).
+func parseArgStrings(args []string) []interface{} {
+	var converted []interface{}
+	for _, arg := range args {
+		arg = strings.TrimSpace(arg)
+		switch {
+		case common.IsHexAddress(arg):
+			converted = append(converted, common.HexToAddress(arg))
+		case strings.HasPrefix(arg, "0x"):
+			if n, success := new(big.Int).SetString(arg[2:], 16); success {
+				converted = append(converted, n)
+				continue
+			}
+			converted = append(converted, arg)
+		default:
+			if n, success := new(big.Int).SetString(arg, 10); success {
+				converted = append(converted, n)
+			} else {
+				converted = append(converted, arg)
+			}
+		}
+	}
+	return converted
+}
@@ -0,0 +1,325 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// RenderOptions configures how a ResultRenderer formats a call's decoded outputs.
+type RenderOptions struct {
+	// Convert enables decimal rendering of *big.Int values (e.g. token amounts).
+	Convert bool
+	// Decimals is the exponent used when Convert is set (10^Decimals denominator).
+	Decimals int
+	// Raw holds the still-ABI-encoded return data; only used by the raw-hex renderer.
+	Raw []byte
+}
+
+// ResultRenderer formats the decoded return values of a contract call for display, naming each
+// value from the method's ABI outputs instead of a bare positional index.
+type ResultRenderer interface {
+	Render(outputs abi.Arguments, results []interface{}, opts RenderOptions) (string, error)
+}
+
+// NewRenderer returns the ResultRenderer for the named -format flag value.
+func NewRenderer(format string) (ResultRenderer, error) {
+	switch format {
+	case "", "text":
+		return textRenderer{}, nil
+	case "json":
+		return jsonRenderer{}, nil
+	case "csv":
+		return csvRenderer{}, nil
+	case "raw-hex":
+		return rawHexRenderer{}, nil
+	default:
+		return nil, fmt.Errorf("unknown -format %q (expected text, json, csv, or raw-hex)", format)
+	}
+}
+
+// resolveDecimals turns the -decimals and -unit flags into a single exponent to divide
+// *big.Int results by. -unit overrides -decimals for the standard wei/gwei/ether denominations;
+// -decimals=auto fetches the contract's own decimals() (only valid with -type=erc20).
+func resolveDecimals(contract *GenericContract, unit, decimalsFlag, contractType string) (int, error) {
+	switch unit {
+	case "wei":
+		return 0, nil
+	case "gwei":
+		return 9, nil
+	case "ether":
+		return 18, nil
+	case "", "token":
+		// fall through to -decimals below
+	default:
+		return 0, fmt.Errorf("unknown -unit %q (expected wei, gwei, ether, or token)", unit)
+	}
+
+	if decimalsFlag != "auto" {
+		var n int
+		if _, err := fmt.Sscanf(decimalsFlag, "%d", &n); err != nil {
+			return 0, fmt.Errorf("invalid -decimals %q: %v", decimalsFlag, err)
+		}
+		return n, nil
+	}
+
+	if contractType != "erc20" {
+		return 0, fmt.Errorf("-decimals=auto requires -type=erc20")
+	}
+
+	result, err := contract.CallViewFunction("decimals", nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to auto-fetch decimals(): %v", err)
+	}
+	if len(result) == 0 {
+		return 0, fmt.Errorf("decimals() returned no value")
+	}
+
+	d, ok := result[0].(uint8)
+	if !ok {
+		return 0, fmt.Errorf("decimals() returned unexpected type %T", result[0])
+	}
+	return int(d), nil
+}
+
+// outputName returns the ABI-declared name of the i-th return value, falling back to a
+// positional label when the ABI leaves it unnamed (common for single-return functions).
+func outputName(outputs abi.Arguments, i int) string {
+	if i < len(outputs) && outputs[i].Name != "" {
+		return outputs[i].Name
+	}
+	return fmt.Sprintf("Result[%d]", i)
+}
+
+// formatDecimal renders v as a decimal string with denominator 10^decimals, matching the
+// behavior of the original -convert flag (integer part, fractional part with trailing zeros
+// trimmed).
+func formatDecimal(v *big.Int, decimals int) string {
+	if decimals <= 0 {
+		return v.String()
+	}
+
+	denom := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	intPart := new(big.Int).Div(new(big.Int).Set(v), denom)
+	fracPart := new(big.Int).Mod(new(big.Int).Set(v), denom)
+
+	fracStr := fracPart.String()
+	for len(fracStr) < decimals {
+		fracStr = "0" + fracStr
+	}
+	for len(fracStr) > 0 && fracStr[len(fracStr)-1] == '0' {
+		fracStr = fracStr[:len(fracStr)-1]
+	}
+
+	if fracStr == "" {
+		return intPart.String()
+	}
+	return intPart.String() + "." + fracStr
+}
+
+// normalizeValue recursively converts a decoded ABI value (addresses, byte arrays, tuples,
+// fixed/dynamic arrays, and primitives) into plain Go types suitable for JSON/CSV encoding.
+func normalizeValue(v interface{}, opts RenderOptions) interface{} {
+	switch val := v.(type) {
+	case *big.Int:
+		if opts.Convert && val.Sign() > 0 {
+			return formatDecimal(val, opts.Decimals)
+		}
+		return val.String()
+	case []byte:
+		return fmt.Sprintf("0x%x", val)
+	case common.Address:
+		return val.Hex()
+	case string:
+		return val
+	case bool:
+		return val
+	default:
+		rv := reflect.ValueOf(v)
+		switch rv.Kind() {
+		case reflect.Array:
+			if rv.Type().Elem().Kind() == reflect.Uint8 {
+				buf := make([]byte, rv.Len())
+				reflect.Copy(reflect.ValueOf(buf), rv)
+				return fmt.Sprintf("0x%x", buf)
+			}
+			return normalizeSlice(rv, opts)
+		case reflect.Slice:
+			return normalizeSlice(rv, opts)
+		case reflect.Struct:
+			return normalizeStruct(rv, opts)
+		default:
+			return fmt.Sprintf("%v", v)
+		}
+	}
+}
+
+func normalizeSlice(rv reflect.Value, opts RenderOptions) []interface{} {
+	items := make([]interface{}, rv.Len())
+	for i := range items {
+		items[i] = normalizeValue(rv.Index(i).Interface(), opts)
+	}
+	return items
+}
+
+func normalizeStruct(rv reflect.Value, opts RenderOptions) map[string]interface{} {
+	t := rv.Type()
+	obj := make(map[string]interface{}, rv.NumField())
+	for i := 0; i < rv.NumField(); i++ {
+		if t.Field(i).PkgPath != "" {
+			continue // unexported field
+		}
+		obj[t.Field(i).Name] = normalizeValue(rv.Field(i).Interface(), opts)
+	}
+	return obj
+}
+
+// textRenderer reproduces the tool's original human-readable output, extended to recurse into
+// tuples and arrays instead of falling back to a bare %v.
+type textRenderer struct{}
+
+func (textRenderer) Render(outputs abi.Arguments, results []interface{}, opts RenderOptions) (string, error) {
+	var b strings.Builder
+	for i, result := range results {
+		fmt.Fprintf(&b, "%s: ", outputName(outputs, i))
+		renderTextValue(&b, result, opts, 0)
+		b.WriteByte('\n')
+	}
+	return b.String(), nil
+}
+
+func renderTextValue(b *strings.Builder, v interface{}, opts RenderOptions, indent int) {
+	pad := strings.Repeat("  ", indent)
+
+	switch val := v.(type) {
+	case *big.Int:
+		fmt.Fprintf(b, "%s (big.Int)", val.String())
+		if opts.Convert && val.Sign() > 0 {
+			fmt.Fprintf(b, " = %s (decimal)", formatDecimal(val, opts.Decimals))
+		}
+	case string:
+		fmt.Fprintf(b, "%s (string)", val)
+	case []byte:
+		fmt.Fprintf(b, "0x%x (bytes)", val)
+	case common.Address:
+		fmt.Fprintf(b, "%s (address)", val.Hex())
+	case bool:
+		fmt.Fprintf(b, "%t (bool)", val)
+	default:
+		rv := reflect.ValueOf(v)
+		switch rv.Kind() {
+		case reflect.Array:
+			if rv.Type().Elem().Kind() == reflect.Uint8 {
+				buf := make([]byte, rv.Len())
+				reflect.Copy(reflect.ValueOf(buf), rv)
+				fmt.Fprintf(b, "0x%x (bytes%d)", buf, rv.Len())
+				return
+			}
+			renderTextSlice(b, rv, opts, indent, pad)
+		case reflect.Slice:
+			renderTextSlice(b, rv, opts, indent, pad)
+		case reflect.Struct:
+			renderTextStruct(b, rv, opts, indent, pad)
+		default:
+			fmt.Fprintf(b, "%v (type: %T)", v, v)
+		}
+	}
+}
+
+func renderTextSlice(b *strings.Builder, rv reflect.Value, opts RenderOptions, indent int, pad string) {
+	if rv.Len() == 0 {
+		b.WriteString("[]")
+		return
+	}
+
+	b.WriteString("[\n")
+	for i := 0; i < rv.Len(); i++ {
+		fmt.Fprintf(b, "%s  [%d]: ", pad, i)
+		renderTextValue(b, rv.Index(i).Interface(), opts, indent+1)
+		b.WriteByte('\n')
+	}
+	fmt.Fprintf(b, "%s]", pad)
+}
+
+func renderTextStruct(b *strings.Builder, rv reflect.Value, opts RenderOptions, indent int, pad string) {
+	t := rv.Type()
+	b.WriteString("{\n")
+	for i := 0; i < rv.NumField(); i++ {
+		if t.Field(i).PkgPath != "" {
+			continue
+		}
+		fmt.Fprintf(b, "%s  %s: ", pad, t.Field(i).Name)
+		renderTextValue(b, rv.Field(i).Interface(), opts, indent+1)
+		b.WriteByte('\n')
+	}
+	fmt.Fprintf(b, "%s}", pad)
+}
+
+// jsonRenderer renders results as a single JSON object keyed by output name.
+type jsonRenderer struct{}
+
+func (jsonRenderer) Render(outputs abi.Arguments, results []interface{}, opts RenderOptions) (string, error) {
+	obj := make(map[string]interface{}, len(results))
+	for i, result := range results {
+		obj[outputName(outputs, i)] = normalizeValue(result, opts)
+	}
+
+	data, err := json.MarshalIndent(obj, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JSON result: %v", err)
+	}
+	return string(data) + "\n", nil
+}
+
+// csvRenderer renders results as a two-line CSV: a header of output names followed by one data
+// row. Nested values (tuples, arrays) are JSON-encoded within their cell.
+type csvRenderer struct{}
+
+func (csvRenderer) Render(outputs abi.Arguments, results []interface{}, opts RenderOptions) (string, error) {
+	header := make([]string, len(results))
+	row := make([]string, len(results))
+	for i, result := range results {
+		header[i] = outputName(outputs, i)
+		row[i] = csvField(normalizeValue(result, opts))
+	}
+
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	if err := w.Write(header); err != nil {
+		return "", fmt.Errorf("failed to write CSV header: %v", err)
+	}
+	if err := w.Write(row); err != nil {
+		return "", fmt.Errorf("failed to write CSV row: %v", err)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", fmt.Errorf("failed to flush CSV output: %v", err)
+	}
+
+	return b.String(), nil
+}
+
+func csvField(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
+
+// rawHexRenderer prints the still-ABI-encoded return data untouched, for debugging a decode
+// mismatch or feeding another tool.
+type rawHexRenderer struct{}
+
+func (rawHexRenderer) Render(outputs abi.Arguments, results []interface{}, opts RenderOptions) (string, error) {
+	return fmt.Sprintf("0x%x\n", opts.Raw), nil
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// chainConfig describes the endpoints used to auto-resolve a contract's ABI on a given chain.
+type chainConfig struct {
+	chainID      int64
+	etherscanAPI string
+}
+
+// chainConfigs maps the -chain flag's accepted names to their numeric chain ID and
+// Etherscan-compatible explorer API base URL.
+var chainConfigs = map[string]chainConfig{
+	"mainnet":  {1, "https://api.etherscan.io/api"},
+	"sepolia":  {11155111, "https://api-sepolia.etherscan.io/api"},
+	"polygon":  {137, "https://api.polygonscan.com/api"},
+	"arbitrum": {42161, "https://api.arbiscan.io/api"},
+	"story":    {1514, "https://www.storyscan.xyz/api"},
+}
+
+// abiCacheEntry is the on-disk format for a cached ABI lookup, keyed by the contract's
+// runtime bytecode hash so that upgrades invalidate stale entries.
+type abiCacheEntry struct {
+	CodeHash string `json:"codeHash"`
+	ABI      string `json:"abi"`
+}
+
+// sourcifyMetadata is the subset of a Sourcify metadata.json this tool cares about.
+type sourcifyMetadata struct {
+	Output struct {
+		ABI json.RawMessage `json:"abi"`
+	} `json:"output"`
+}
+
+// etherscanResponse is the common envelope returned by Etherscan-compatible getabi endpoints.
+type etherscanResponse struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Result  string `json:"result"`
+}
+
+var httpClient = &http.Client{Timeout: 15 * time.Second}
+
+// chainNameForID returns the chainConfigs key whose chain ID matches id, used to auto-detect
+// -chain from the connected RPC's reported chain ID when -chain is left unset.
+func chainNameForID(id int64) (string, bool) {
+	for name, cfg := range chainConfigs {
+		if cfg.chainID == id {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// ResolveABI fetches the ABI for address on the named chain, trying Sourcify and then an
+// Etherscan-compatible explorer, and caches the result under
+// ~/.story-contract-tool/abi-cache/<chainId>/<address>.json keyed by the contract's current
+// runtime bytecode hash.
+//
+// chainName is cross-checked against the chain ID reported by client (the -rpc endpoint): -rpc
+// and -chain are independent flags, and a contract address can exist with unrelated bytecode on
+// every chain, so resolving against the wrong chain would silently fetch and cache the wrong ABI
+// instead of failing. Leaving chainName empty auto-detects it from the RPC's chain ID.
+func ResolveABI(ctx context.Context, client *ethclient.Client, address common.Address, chainName, etherscanKey string) (string, error) {
+	rpcChainID, err := client.ChainID(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch chain ID from -rpc: %v", err)
+	}
+
+	if chainName == "" {
+		detected, ok := chainNameForID(rpcChainID.Int64())
+		if !ok {
+			return "", fmt.Errorf("no known -chain for RPC chain ID %s; pass -chain explicitly (known chains: mainnet, sepolia, polygon, arbitrum, story)", rpcChainID)
+		}
+		chainName = detected
+	}
+
+	cfg, ok := chainConfigs[chainName]
+	if !ok {
+		return "", fmt.Errorf("unknown chain %q (known chains: mainnet, sepolia, polygon, arbitrum, story)", chainName)
+	}
+	if cfg.chainID != rpcChainID.Int64() {
+		return "", fmt.Errorf("-chain=%s expects chain ID %d but -rpc reports chain ID %s; pass the matching -chain or omit it to auto-detect", chainName, cfg.chainID, rpcChainID)
+	}
+
+	code, err := client.CodeAt(ctx, address, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch bytecode for %s: %v", address.Hex(), err)
+	}
+	if len(code) == 0 {
+		return "", fmt.Errorf("no bytecode found at %s (not a contract on this chain?)", address.Hex())
+	}
+	hash := sha256.Sum256(code)
+	codeHash := hex.EncodeToString(hash[:])
+
+	if cached, ok := loadCachedABI(cfg.chainID, address, codeHash); ok {
+		return cached, nil
+	}
+
+	abiString, err := fetchFromSourcify(cfg.chainID, address)
+	if err != nil {
+		abiString, err = fetchFromEtherscan(cfg.etherscanAPI, address, etherscanKey)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve ABI from Sourcify or Etherscan: %v", err)
+		}
+	}
+
+	if err := storeCachedABI(cfg.chainID, address, codeHash, abiString); err != nil {
+		log.Printf("Warning: failed to cache resolved ABI: %v", err)
+	}
+
+	return abiString, nil
+}
+
+// fetchFromSourcify retrieves a verified contract's full-match metadata from Sourcify and
+// extracts its ABI.
+func fetchFromSourcify(chainID int64, address common.Address) (string, error) {
+	url := fmt.Sprintf("https://repo.sourcify.dev/contracts/full_match/%d/%s/metadata.json", chainID, address.Hex())
+
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return "", fmt.Errorf("sourcify request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("sourcify returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read sourcify response: %v", err)
+	}
+
+	var metadata sourcifyMetadata
+	if err := json.Unmarshal(body, &metadata); err != nil {
+		return "", fmt.Errorf("failed to parse sourcify metadata: %v", err)
+	}
+	if len(metadata.Output.ABI) == 0 {
+		return "", fmt.Errorf("sourcify metadata did not contain an ABI")
+	}
+
+	return string(metadata.Output.ABI), nil
+}
+
+// fetchFromEtherscan retrieves a verified contract's ABI from an Etherscan-compatible explorer.
+func fetchFromEtherscan(apiBase string, address common.Address, apiKey string) (string, error) {
+	url := fmt.Sprintf("%s?module=contract&action=getabi&address=%s&apikey=%s", apiBase, address.Hex(), apiKey)
+
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return "", fmt.Errorf("etherscan request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read etherscan response: %v", err)
+	}
+
+	var parsed etherscanResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return "", fmt.Errorf("failed to parse etherscan response: %v", err)
+	}
+	if parsed.Status != "1" {
+		return "", fmt.Errorf("etherscan returned an error: %s", parsed.Message)
+	}
+
+	return parsed.Result, nil
+}
+
+// abiCacheDir returns the directory holding cached ABIs for chainID, creating it if necessary.
+func abiCacheDir(chainID int64) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory: %v", err)
+	}
+
+	dir := filepath.Join(home, ".story-contract-tool", "abi-cache", fmt.Sprintf("%d", chainID))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create ABI cache directory: %v", err)
+	}
+
+	return dir, nil
+}
+
+// loadCachedABI returns the cached ABI for address on chainID, if present and still keyed by
+// the given bytecode hash.
+func loadCachedABI(chainID int64, address common.Address, codeHash string) (string, bool) {
+	dir, err := abiCacheDir(chainID)
+	if err != nil {
+		return "", false
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, address.Hex()+".json"))
+	if err != nil {
+		return "", false
+	}
+
+	var entry abiCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return "", false
+	}
+	if entry.CodeHash != codeHash {
+		return "", false
+	}
+
+	return entry.ABI, true
+}
+
+// storeCachedABI writes abiString to the on-disk cache for address on chainID, keyed by codeHash.
+func storeCachedABI(chainID int64, address common.Address, codeHash, abiString string) error {
+	dir, err := abiCacheDir(chainID)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(abiCacheEntry{CodeHash: codeHash, ABI: abiString})
+	if err != nil {
+		return fmt.Errorf("failed to marshal ABI cache entry: %v", err)
+	}
+
+	return os.WriteFile(filepath.Join(dir, address.Hex()+".json"), data, 0o644)
+}
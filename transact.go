@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// SendTransaction sends a state-changing call to functionName using opts to sign and
+// broadcast it, via bind.BoundContract.Transact.
+func (gc *GenericContract) SendTransaction(functionName string, opts *bind.TransactOpts, args ...interface{}) (*types.Transaction, error) {
+	tx, err := gc.contract.Transact(opts, functionName, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send transaction for function %q: %v", functionName, err)
+	}
+	return tx, nil
+}
+
+// sendOpts bundles the -send related CLI flags so they can be threaded through runSendMode
+// without a long parameter list.
+type sendOpts struct {
+	functionName   string
+	keystorePath   string
+	passphrase     string
+	passphraseFile string
+	gasLimit       uint64
+	gasPrice       string
+	maxFee         string
+	maxPriorityFee string
+	value          string
+	nonce          int64
+	chainID        string
+}
+
+// runSendMode builds a *bind.TransactOpts from a keystore file and the -send CLI flags, sends
+// the transaction, waits for it to be mined, and prints the resulting receipt.
+func runSendMode(contract *GenericContract, client *ethclient.Client, opts sendOpts, args ...interface{}) {
+	if opts.keystorePath == "" {
+		log.Fatalf("Error: -keystore is required with -send")
+	}
+	if opts.chainID == "" {
+		log.Fatalf("Error: -chain-id is required with -send")
+	}
+
+	chainID, success := new(big.Int).SetString(opts.chainID, 10)
+	if !success {
+		log.Fatalf("Failed to parse -chain-id value: %s", opts.chainID)
+	}
+
+	passphrase := opts.passphrase
+	if opts.passphraseFile != "" {
+		data, err := os.ReadFile(opts.passphraseFile)
+		if err != nil {
+			log.Fatalf("Failed to read passphrase file: %v", err)
+		}
+		passphrase = strings.TrimRight(string(data), "\r\n")
+	}
+
+	keyFile, err := os.Open(opts.keystorePath)
+	if err != nil {
+		log.Fatalf("Failed to open keystore file: %v", err)
+	}
+	defer keyFile.Close()
+
+	transactOpts, err := bind.NewTransactorWithChainID(keyFile, passphrase, chainID)
+	if err != nil {
+		log.Fatalf("Failed to create transactor from keystore: %v", err)
+	}
+	transactOpts.Context = context.Background()
+	transactOpts.GasLimit = opts.gasLimit
+
+	if opts.nonce >= 0 {
+		transactOpts.Nonce = big.NewInt(opts.nonce)
+	}
+
+	if opts.value != "" {
+		value, success := new(big.Int).SetString(opts.value, 10)
+		if !success {
+			log.Fatalf("Failed to parse -value: %s", opts.value)
+		}
+		transactOpts.Value = value
+	}
+
+	switch {
+	case opts.maxFee != "" || opts.maxPriorityFee != "":
+		maxFee, success := new(big.Int).SetString(opts.maxFee, 10)
+		if !success {
+			log.Fatalf("Failed to parse -max-fee: %s", opts.maxFee)
+		}
+		maxPriorityFee, success := new(big.Int).SetString(opts.maxPriorityFee, 10)
+		if !success {
+			log.Fatalf("Failed to parse -max-priority-fee: %s", opts.maxPriorityFee)
+		}
+		transactOpts.GasFeeCap = maxFee
+		transactOpts.GasTipCap = maxPriorityFee
+	case opts.gasPrice != "":
+		gasPrice, success := new(big.Int).SetString(opts.gasPrice, 10)
+		if !success {
+			log.Fatalf("Failed to parse -gas-price: %s", opts.gasPrice)
+		}
+		transactOpts.GasPrice = gasPrice
+	}
+
+	fmt.Printf("Sending transaction to function '%s' with %d arguments\n", opts.functionName, len(args))
+	tx, err := contract.SendTransaction(opts.functionName, transactOpts, args...)
+	if err != nil {
+		log.Fatalf("Failed to send transaction: %v", err)
+	}
+
+	fmt.Printf("Transaction sent: %s\n", tx.Hash().Hex())
+	fmt.Println("Waiting for transaction to be mined...")
+
+	receipt, err := bind.WaitMined(transactOpts.Context, client, tx)
+	if err != nil {
+		log.Fatalf("Failed waiting for transaction receipt: %v", err)
+	}
+
+	status := "failed"
+	if receipt.Status == types.ReceiptStatusSuccessful {
+		status = "success"
+	}
+	fmt.Printf("Transaction mined in block %d | status: %s | gas used: %d\n", receipt.BlockNumber.Uint64(), status, receipt.GasUsed)
+
+	for i, logEntry := range receipt.Logs {
+		eventName, ok := matchEventByTopic(contract.parsedABI, *logEntry)
+		if !ok {
+			fmt.Printf("Log[%d]: address=%s topics=%d\n", i, logEntry.Address.Hex(), len(logEntry.Topics))
+			continue
+		}
+
+		decoded, err := contract.DecodeEventLog(eventName, *logEntry)
+		if err != nil {
+			fmt.Printf("Log[%d]: address=%s event=%s (failed to decode: %v)\n", i, logEntry.Address.Hex(), eventName, err)
+			continue
+		}
+
+		fmt.Printf("Log[%d]: address=%s event=%s\n", i, logEntry.Address.Hex(), eventName)
+		for name, value := range decoded {
+			fmt.Printf("  %s: %v\n", name, value)
+		}
+	}
+}